@@ -0,0 +1,63 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"io"
+	"os"
+	"path"
+)
+
+// ArtifactEntry identifies one artifact file available from an ArtifactSource.
+type ArtifactEntry struct {
+	// Name is the artifact's file name, e.g. "go.1.21.0-1.linux-amd64.tar.gz". It has no directory
+	// component: ArtifactSource implementations are expected to list a single flat directory of
+	// build outputs, matching how Microsoft Go builds stage their artifacts.
+	Name string
+}
+
+// ArtifactSource abstracts where a build's output artifacts (.tar.gz, .zip, .sha256, .sig files)
+// can be read from, so BuildResultsSourceInfo.CreateSummary can produce a summary directly from
+// artifacts that are already uploaded to their destination, without staging them locally first.
+type ArtifactSource interface {
+	// List returns every artifact file available from this source.
+	List() ([]ArtifactEntry, error)
+	// Open returns a reader for the named artifact's content. The caller must Close it.
+	Open(name string) (io.ReadCloser, error)
+}
+
+// NewLocalDirArtifactSource returns an ArtifactSource backed by a plain local directory of
+// artifacts, the same kind BuildResultsDirectoryInfo uses internally. Callers that already have
+// artifacts staged locally (e.g. cmd/verifyassets validating a downloaded directory) can use this
+// directly instead of going through BuildResultsDirectoryInfo.
+func NewLocalDirArtifactSource(dir string) ArtifactSource {
+	return localDirArtifactSource{dir: dir}
+}
+
+// localDirArtifactSource is the ArtifactSource backing BuildResultsDirectoryInfo and
+// NewLocalDirArtifactSource, reading artifacts from a plain local directory.
+type localDirArtifactSource struct {
+	dir string
+}
+
+// List implements ArtifactSource.
+func (s localDirArtifactSource) List() ([]ArtifactEntry, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ArtifactEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		result = append(result, ArtifactEntry{Name: e.Name()})
+	}
+	return result, nil
+}
+
+// Open implements ArtifactSource.
+func (s localDirArtifactSource) Open(name string) (io.ReadCloser, error) {
+	return os.Open(path.Join(s.dir, name))
+}
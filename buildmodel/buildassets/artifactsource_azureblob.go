@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+// AzureBlobArtifactSource is an ArtifactSource backed by a container in Azure Blob Storage, the
+// destination Microsoft's Go build pipelines upload release artifacts to.
+type AzureBlobArtifactSource struct {
+	// Client is an already-authenticated client for the container to read artifacts from.
+	Client *container.Client
+	// Prefix is the blob name prefix (acting like a directory) that this build's artifacts were
+	// uploaded under, e.g. "go/1.21.0-1/". List and Open only see names under this prefix, with
+	// the prefix itself stripped from ArtifactEntry.Name.
+	Prefix string
+}
+
+// List implements ArtifactSource by listing blobs under Prefix.
+func (s AzureBlobArtifactSource) List() ([]ArtifactEntry, error) {
+	var entries []ArtifactEntry
+
+	pager := s.Client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix: &s.Prefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, blob := range page.Segment.BlobItems {
+			if blob.Name == nil {
+				continue
+			}
+			name := strings.TrimPrefix(*blob.Name, s.Prefix)
+			if name == "" || strings.Contains(name, "/") {
+				// Skip the prefix "directory" itself and any nested "subdirectories": build
+				// artifacts are staged as a flat list, matching localDirArtifactSource.
+				continue
+			}
+			entries = append(entries, ArtifactEntry{Name: name})
+		}
+	}
+	return entries, nil
+}
+
+// Open implements ArtifactSource by downloading the blob at Prefix+name.
+func (s AzureBlobArtifactSource) Open(name string) (io.ReadCloser, error) {
+	blobClient := s.Client.NewBlobClient(path.Join(s.Prefix, name))
+	resp, err := blobClient.DownloadStream(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
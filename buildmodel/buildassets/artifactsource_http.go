@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// HTTPIndexArtifactSource is an ArtifactSource backed by artifacts published under a plain HTTP(S)
+// URL, such as a static file host that doesn't offer an API. It supports either an Apache/nginx
+// style autoindex HTML directory listing, or a small JSON index file, whichever the host provides.
+type HTTPIndexArtifactSource struct {
+	// BaseURL is the URL artifact file names are joined onto to download them, e.g.
+	// "https://example.com/go/1.21.0-1/". Must end with a trailing slash.
+	BaseURL string
+	// IndexURL, if set, is fetched and decoded as a JSON array of artifact file names instead of
+	// parsing an HTML directory listing at BaseURL. Use this for hosts that don't serve directory
+	// listings.
+	IndexURL string
+	// Client is the HTTP client used for all requests. Defaults to http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// hrefPattern extracts href attribute values from anchor tags, the way a plain autoindex HTML
+// directory listing (Apache's mod_autoindex, nginx's autoindex on) presents its entries.
+var hrefPattern = regexp.MustCompile(`(?i)<a\s+[^>]*href="([^"]+)"`)
+
+func (s HTTPIndexArtifactSource) httpClient() *http.Client {
+	if s.Client != nil {
+		return s.Client
+	}
+	return http.DefaultClient
+}
+
+// List implements ArtifactSource by fetching IndexURL (if set) or parsing the HTML directory
+// listing at BaseURL.
+func (s HTTPIndexArtifactSource) List() ([]ArtifactEntry, error) {
+	if s.IndexURL != "" {
+		return s.listFromJSONIndex()
+	}
+	return s.listFromHTMLIndex()
+}
+
+func (s HTTPIndexArtifactSource) listFromJSONIndex() ([]ArtifactEntry, error) {
+	body, err := s.get(s.IndexURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var names []string
+	if err := json.NewDecoder(body).Decode(&names); err != nil {
+		return nil, fmt.Errorf("unable to parse JSON index: %w", err)
+	}
+
+	entries := make([]ArtifactEntry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, ArtifactEntry{Name: name})
+	}
+	return entries, nil
+}
+
+func (s HTTPIndexArtifactSource) listFromHTMLIndex() ([]ArtifactEntry, error) {
+	body, err := s.get(s.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	html, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ArtifactEntry
+	for _, m := range hrefPattern.FindAllStringSubmatch(string(html), -1) {
+		href := m[1]
+		// Skip parent-directory links, query strings, and nested paths: artifacts are staged as a
+		// flat list, matching localDirArtifactSource.
+		if href == "" || href == "../" || strings.Contains(href, "?") || strings.Contains(strings.TrimSuffix(href, "/"), "/") {
+			continue
+		}
+		if strings.HasSuffix(href, "/") {
+			continue
+		}
+		entries = append(entries, ArtifactEntry{Name: href})
+	}
+	return entries, nil
+}
+
+// Open implements ArtifactSource by GETting BaseURL+name.
+func (s HTTPIndexArtifactSource) Open(name string) (io.ReadCloser, error) {
+	return s.get(s.BaseURL + name)
+}
+
+// get performs a GET request and returns the body, or an error if the request failed or didn't
+// return 200 OK.
+func (s HTTPIndexArtifactSource) get(url string) (io.ReadCloser, error) {
+	resp, err := s.httpClient().Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %v: unexpected status %v", url, resp.Status)
+	}
+	return resp.Body, nil
+}
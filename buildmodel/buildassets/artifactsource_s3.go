@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3ArtifactSource is an ArtifactSource backed by a prefix in an S3 (or S3-compatible) bucket.
+type S3ArtifactSource struct {
+	// Client is an already-authenticated S3 client to read artifacts with.
+	Client *s3.Client
+	// Bucket is the S3 bucket the artifacts were uploaded to.
+	Bucket string
+	// Prefix is the key prefix the artifacts were uploaded under, e.g. "go/1.21.0-1/". List and
+	// Open only see keys under this prefix, with the prefix itself stripped from
+	// ArtifactEntry.Name.
+	Prefix string
+}
+
+// List implements ArtifactSource by listing objects under Bucket/Prefix.
+func (s S3ArtifactSource) List() ([]ArtifactEntry, error) {
+	var entries []ArtifactEntry
+
+	paginator := s3.NewListObjectsV2Paginator(s.Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.Bucket),
+		Prefix: aws.String(s.Prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			if obj.Key == nil {
+				continue
+			}
+			name := strings.TrimPrefix(*obj.Key, s.Prefix)
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			entries = append(entries, ArtifactEntry{Name: name})
+		}
+	}
+	return entries, nil
+}
+
+// Open implements ArtifactSource by downloading the object at Bucket/Prefix+name.
+func (s S3ArtifactSource) Open(name string) (io.ReadCloser, error) {
+	resp, err := s.Client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(s.Prefix + name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
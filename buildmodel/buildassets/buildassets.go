@@ -12,8 +12,8 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path"
 	"sort"
 	"strings"
 
@@ -29,20 +29,48 @@ type BuildAssets struct {
 
 	// Version of the build, as 'major.minor.patch-revision'.
 	Version string `json:"version"`
+	// Track categorizes this build for Go Docker tag/branch selection: "stable", "beta", "rc",
+	// "boringcrypto", or "fips". See VersionInfo.Track.
+	Track Track `json:"track,omitempty"`
+	// MajorMinor is the "major.minor" portion of Version, e.g. "1.21". Empty if it couldn't be
+	// determined from the source tree (see VersionInfo.MajorMinor).
+	MajorMinor string `json:"majorMinor,omitempty"`
+	// Prerelease is the prerelease suffix, e.g. "rc1" or "beta2", or "" for a final release. See
+	// VersionInfo.Prerelease.
+	Prerelease string `json:"prerelease,omitempty"`
+	// CommitHash is the short Git commit hash of the source tree that was built, if available.
+	CommitHash string `json:"commitHash,omitempty"`
 	// Arches is the list of artifacts that was produced for this version, typically one per target
 	// os/architecture. The name "Arches" is shared with the versions.json format.
 	Arches []*dockerversions.Arch `json:"arches"`
+
+	// Signatures holds detached signatures over this manifest itself (everything above this
+	// field), keyed by the signer's Name, e.g. "gpg" or "cosign". Populated by SignSummary and
+	// checked by VerifySummary. This authenticates the manifest as a whole; the per-arch
+	// dockerversions.Arch.Signature fields separately authenticate each archive.
+	Signatures map[string]string `json:"signatures,omitempty"`
 }
 
 // GetDockerRepoTargetBranch returns the Go Docker images repo branch that needs to be updated based
-// on the branch of the Go repo that was built, or returns empty string if no branch needs to be
-// updated.
+// on the Track of the build, or returns empty string if no branch needs to be updated. Keying off
+// Track instead of matching Branch prefixes directly means adding a new release track (e.g. a
+// future FIPS-for-a-different-cert-family variant) only requires teaching detectTrack about its
+// branch naming, not this function.
+//
+// Track is empty ("") for any BuildAssets that predates this field, including every manifest
+// already published and anything produced by older or external tooling. That's treated the same
+// as TrackStable below, so historical manifests keep working the same way they always did.
 func (b BuildAssets) GetDockerRepoTargetBranch() string {
-	if b.Branch == "main" || strings.HasPrefix(b.Branch, "release-branch.") {
-		return "microsoft/main"
-	}
-	if strings.HasPrefix(b.Branch, "dev/official/") {
+	switch b.Track {
+	case TrackBoringCrypto, TrackFIPS:
 		return b.Branch
+	case TrackStable, TrackBeta, TrackRC, "":
+		if b.Branch == "main" || strings.HasPrefix(b.Branch, "release-branch.") {
+			return "microsoft/main"
+		}
+		if strings.HasPrefix(b.Branch, "dev/official/") {
+			return b.Branch
+		}
 	}
 	return ""
 }
@@ -53,6 +81,32 @@ func (b BuildAssets) GetDockerRepoTargetBranch() string {
 var archiveSuffixes = []string{".tar.gz", ".zip"}
 var checksumSuffix = ".sha256"
 
+// signatureSuffixSpec pairs a detached signature file suffix with the SignatureKind it implies.
+type signatureSuffixSpec struct {
+	suffix string
+	kind   string
+}
+
+// signatureSuffixes are the detached signature files CreateSummary recognizes alongside an
+// archive's checksum file: either a signature over the archive itself, or over its .sha256 file.
+// The latter is smaller to sign and is enough to transitively authenticate the archive. Ordered
+// longest-first so ".sha256.sig" is matched before the shorter ".sig" suffix it also satisfies.
+var signatureSuffixes = []signatureSuffixSpec{
+	{".sha256.sig", dockerversions.SignatureKindSHA256},
+	{".sig", dockerversions.SignatureKindArchive},
+}
+
+// matchSignatureSuffix returns the longest signature suffix name ends with, and the SignatureKind
+// it implies, if any.
+func matchSignatureSuffix(name string) (suffix, kind string, ok bool) {
+	for _, spec := range signatureSuffixes {
+		if strings.HasSuffix(name, spec.suffix) {
+			return spec.suffix, spec.kind, true
+		}
+	}
+	return "", "", false
+}
+
 // BuildResultsDirectoryInfo points to locations in the filesystem that contain a Go build from
 // source, and includes extra information that helps make sense of the build results.
 type BuildResultsDirectoryInfo struct {
@@ -73,26 +127,114 @@ type BuildResultsDirectoryInfo struct {
 	// BuildID uniquely identifies the CI pipeline build that produced this result. This allows devs
 	// to quickly trace back to the originating build if something goes wrong later on.
 	BuildID string
+	// SynthesizeDarwinUniversal opts in to detecting a darwin-amd64/darwin-arm64 archive pair and
+	// synthesizing an additional darwin-universal archive that fuses both into one Mach-O fat
+	// binary, the same kind of artifact "lipo -create" produces. Off by default so existing
+	// pipelines that don't expect a universal arch are unaffected.
+	SynthesizeDarwinUniversal bool
 }
 
 // CreateSummary scans the paths/info from a BuildResultsDirectoryInfo to summarize the outputs of
 // the build in a BuildAssets struct. The result can be used later to perform an auto-update.
+//
+// It runs the same discovery logic as BuildResultsSourceInfo.CreateSummary (createSummary) against
+// a localDirArtifactSource rooted at ArtifactsDir, preserving the original directory-based API for
+// existing callers, then adds the darwin-universal arch if SynthesizeDarwinUniversal is set.
 func (b BuildResultsDirectoryInfo) CreateSummary() (*BuildAssets, error) {
-	goVersion, err := getVersion(path.Join(b.SourceDir, "VERSION"), "main")
+	var source ArtifactSource
+	if b.ArtifactsDir != "" {
+		source = localDirArtifactSource{dir: b.ArtifactsDir}
+	}
+
+	assets, arches, archiveFileName, err := createSummary(b.SourceDir, b.Branch, b.BuildID, b.DestinationURL, source)
 	if err != nil {
 		return nil, err
 	}
-	goRevision, err := getVersion(path.Join(b.SourceDir, "MICROSOFT_REVISION"), "1")
+
+	if b.SynthesizeDarwinUniversal {
+		universal, err := synthesizeDarwinUniversal(b, arches, archiveFileName)
+		if err != nil {
+			return nil, err
+		}
+		if universal != nil {
+			arches = append(arches, universal)
+			sortArches(arches)
+			assets.Arches = arches
+		}
+	}
+
+	return assets, nil
+}
+
+// BuildResultsSourceInfo is the ArtifactSource-based counterpart of BuildResultsDirectoryInfo: it
+// summarizes a Go build's outputs the same way, but reads the artifacts (.tar.gz, .zip, .sha256)
+// through an ArtifactSource instead of requiring them to be staged in a local directory first.
+// This matches the pattern where CI archives are uploaded to a destination before the summary step
+// runs, so the summary can be produced straight from what's already there.
+type BuildResultsSourceInfo struct {
+	// SourceDir is the path to the source code that was built. This is checked for files that
+	// indicate what version of Go was built. It's still expected to be local, since it's only read
+	// to extract version info, not uploaded anywhere.
+	SourceDir string
+	// Source provides access to the build's output artifacts.
+	Source ArtifactSource
+	// DestinationURL is the URL where the assets are (or will be) available, used to build each
+	// arch's URL in the summary.
+	DestinationURL string
+	// Branch is the Git branch this build was built with.
+	Branch string
+	// BuildID uniquely identifies the CI pipeline build that produced this result.
+	BuildID string
+}
+
+// CreateSummary scans the artifacts available through b.Source to summarize the outputs of the
+// build in a BuildAssets struct, the same way BuildResultsDirectoryInfo.CreateSummary does for a
+// local directory.
+func (b BuildResultsSourceInfo) CreateSummary() (*BuildAssets, error) {
+	assets, _, _, err := createSummary(b.SourceDir, b.Branch, b.BuildID, b.DestinationURL, b.Source)
+	return assets, err
+}
+
+// createSummary holds the discovery logic shared by BuildResultsDirectoryInfo and
+// BuildResultsSourceInfo. It also returns the discovered arches and their archive file names
+// (sorted by URL, pre-universal-synthesis) so BuildResultsDirectoryInfo.CreateSummary can feed them to
+// synthesizeDarwinUniversal, which needs local file access that ArtifactSource doesn't provide.
+func createSummary(sourceDir, branch, buildID, destinationURL string, source ArtifactSource) (*BuildAssets, []*dockerversions.Arch, map[*dockerversions.Arch]string, error) {
+	versionInfo, goVersion, err := getVersionInfo(sourceDir, branch)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	// Go version file content begins with "go", matching the tags, but we just want numbers.
-	goVersion = strings.TrimPrefix(goVersion, "go")
+	arches, archiveFileName, err := discoverArches(source, destinationURL)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
+	sortArches(arches)
+
+	return &BuildAssets{
+		Branch:     branch,
+		BuildID:    buildID,
+		Version:    goVersion + "-" + versionInfo.Revision,
+		Track:      versionInfo.Track,
+		MajorMinor: versionInfo.MajorMinor,
+		Prerelease: versionInfo.Prerelease(),
+		CommitHash: versionInfo.CommitHash,
+		Arches:     arches,
+	}, arches, archiveFileName, nil
+}
+
+// discoverArches lists and reads source's entries, associating each archive with its checksum and
+// signature files the same way a local directory listing would: by matching file names with the
+// archive's name plus a known suffix (".sha256", ".sig", ".sha256.sig").
+func discoverArches(source ArtifactSource, destinationURL string) ([]*dockerversions.Arch, map[*dockerversions.Arch]string, error) {
 	// Store the set of artifacts discovered in a map. This lets us easily associate a "go.tar.gz"
 	// with its "go.tar.gz.sha256" file.
 	archMap := make(map[string]*dockerversions.Arch)
+	// archiveFileName records, for each arch that turned out to be an archive, the file name it
+	// was discovered under. SynthesizeDarwinUniversal needs this to find the amd64/arm64 archives
+	// again; the rest of this function only needs the map key.
+	archiveFileName := make(map[*dockerversions.Arch]string)
 	getOrCreateArch := func(name string) *dockerversions.Arch {
 		if arch, ok := archMap[name]; ok {
 			return arch
@@ -102,48 +244,56 @@ func (b BuildResultsDirectoryInfo) CreateSummary() (*BuildAssets, error) {
 		return a
 	}
 
-	if b.ArtifactsDir != "" {
-		entries, err := os.ReadDir(b.ArtifactsDir)
+	if source != nil {
+		entries, err := source.List()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		for _, e := range entries {
-			if e.IsDir() {
+			fmt.Printf("Artifact file: %v\n", e.Name)
+
+			// Is it a detached signature file? Check this before the checksum file case, because
+			// ".sha256.sig" has the ".sig" suffix but should be associated by trimming the longer one.
+			if sigSuffix, sigKind, ok := matchSignatureSuffix(e.Name); ok {
+				a := getOrCreateArch(strings.TrimSuffix(e.Name, sigSuffix))
+				sig, err := readAll(source, e.Name)
+				if err != nil {
+					return nil, nil, fmt.Errorf("unable to read signature file '%v': %w", e.Name, err)
+				}
+				a.Signature = strings.TrimSpace(string(sig))
+				a.SignatureKind = sigKind
 				continue
 			}
-			fmt.Printf("Artifact file: %v\n", e.Name())
-
-			fullPath := path.Join(b.ArtifactsDir, e.Name())
-
 			// Is it a checksum file?
-			if strings.HasSuffix(e.Name(), checksumSuffix) {
+			if strings.HasSuffix(e.Name, checksumSuffix) {
 				// Find/create the arch that matches up with this checksum file.
-				a := getOrCreateArch(strings.TrimSuffix(e.Name(), checksumSuffix))
+				a := getOrCreateArch(strings.TrimSuffix(e.Name, checksumSuffix))
 				// Extract the checksum column from the file and store it in the summary.
-				checksumLine, err := os.ReadFile(fullPath)
+				checksumLine, err := readAll(source, e.Name)
 				if err != nil {
-					return nil, fmt.Errorf("unable to read checksum file '%v': %w", fullPath, err)
+					return nil, nil, fmt.Errorf("unable to read checksum file '%v': %w", e.Name, err)
 				}
 				a.SHA256 = strings.Fields(string(checksumLine))[0]
 				continue
 			}
 			// Is it an archive?
 			for _, suffix := range archiveSuffixes {
-				if strings.HasSuffix(e.Name(), suffix) {
+				if strings.HasSuffix(e.Name, suffix) {
 					// Extract OS/ARCH from the end of a filename like:
 					// "go.12.{...}.3.4.{GOOS}-{GOARCH}.tar.gz"
-					extensionless := strings.TrimSuffix(e.Name(), suffix)
+					extensionless := strings.TrimSuffix(e.Name, suffix)
 					osArch := extensionless[strings.LastIndex(extensionless, ".")+1:]
 					osArchParts := strings.Split(osArch, "-")
 					goOS, goArch := osArchParts[0], osArchParts[1]
 
-					a := getOrCreateArch(e.Name())
-					a.URL = b.DestinationURL + "/" + e.Name()
+					a := getOrCreateArch(e.Name)
+					a.URL = destinationURL + "/" + e.Name
 					a.Env = dockerversions.ArchEnv{
 						GOOS:   goOS,
 						GOARCH: goArch,
 					}
+					archiveFileName[a] = e.Name
 					break
 				}
 			}
@@ -154,18 +304,25 @@ func (b BuildResultsDirectoryInfo) CreateSummary() (*BuildAssets, error) {
 	for _, v := range archMap {
 		arches = append(arches, v)
 	}
+	return arches, archiveFileName, nil
+}
+
+// readAll opens name in source and reads it fully into memory. It is used for checksum and
+// signature files, and by VerifyArchSignatures for whole archives.
+func readAll(source ArtifactSource, name string) ([]byte, error) {
+	r, err := source.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
 
-	// Sort arch entries by unique field (URL) for stable order.
+// sortArches sorts arch entries by unique field (URL) for stable output order.
+func sortArches(arches []*dockerversions.Arch) {
 	sort.Slice(arches, func(i, j int) bool {
 		return arches[i].URL < arches[j].URL
 	})
-
-	return &BuildAssets{
-		Branch:  b.Branch,
-		BuildID: b.BuildID,
-		Version: goVersion + "-" + goRevision,
-		Arches:  arches,
-	}, nil
 }
 
 // getVersion reads the file at path, if it exists. If it doesn't exist, returns the default
@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"testing"
+
+	"github.com/microsoft/go-infra/buildmodel/dockerversions"
+)
+
+func TestGetDockerRepoTargetBranch(t *testing.T) {
+	tests := []struct {
+		name string
+		b    BuildAssets
+		want string
+	}{
+		{"stable main", BuildAssets{Track: TrackStable, Branch: "main"}, "microsoft/main"},
+		{"stable release branch", BuildAssets{Track: TrackStable, Branch: "release-branch.go1.21"}, "microsoft/main"},
+		{"beta dev/official", BuildAssets{Track: TrackBeta, Branch: "dev/official/go1.22-beta"}, "dev/official/go1.22-beta"},
+		{"rc unrecognized branch", BuildAssets{Track: TrackRC, Branch: "some-other-branch"}, ""},
+		{"boringcrypto", BuildAssets{Track: TrackBoringCrypto, Branch: "dev.boringcrypto"}, "dev.boringcrypto"},
+		{"fips", BuildAssets{Track: TrackFIPS, Branch: "dev/official/go1.21-fips-preview"}, "dev/official/go1.21-fips-preview"},
+		{"empty track, main branch (historical manifest)", BuildAssets{Branch: "main"}, "microsoft/main"},
+		{"empty track, release branch (historical manifest)", BuildAssets{Branch: "release-branch.go1.20"}, "microsoft/main"},
+		{"empty track, unrecognized branch", BuildAssets{Branch: "some-other-branch"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.b.GetDockerRepoTargetBranch(); got != tt.want {
+				t.Errorf("GetDockerRepoTargetBranch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchSignatureSuffix(t *testing.T) {
+	tests := []struct {
+		name       string
+		fileName   string
+		wantSuffix string
+		wantKind   string
+		wantOK     bool
+	}{
+		{"sha256.sig", "go.linux-amd64.tar.gz.sha256.sig", ".sha256.sig", dockerversions.SignatureKindSHA256, true},
+		{"plain sig", "go.linux-amd64.tar.gz.sig", ".sig", dockerversions.SignatureKindArchive, true},
+		{"sha256 checksum file is not a signature", "go.linux-amd64.tar.gz.sha256", "", "", false},
+		{"archive itself is not a signature", "go.linux-amd64.tar.gz", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suffix, kind, ok := matchSignatureSuffix(tt.fileName)
+			if ok != tt.wantOK || suffix != tt.wantSuffix || kind != tt.wantKind {
+				t.Errorf("matchSignatureSuffix(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.fileName, suffix, kind, ok, tt.wantSuffix, tt.wantKind, tt.wantOK)
+			}
+		})
+	}
+}
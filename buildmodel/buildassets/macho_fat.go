@@ -0,0 +1,90 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// This file implements just enough of the Mach-O fat binary format to fuse two thin
+// (single-architecture) executables into one "universal" binary, equivalent to what Apple's
+// "lipo -create" produces. It intentionally doesn't depend on debug/macho's write support (which
+// doesn't exist) or cgo, so archive fusion works the same way on any host OS.
+
+const (
+	fatMagic      = 0xcafebabe
+	macho64Magic  = 0xfeedfacf // 64-bit Mach-O, same-endian as host read (LE on amd64/arm64).
+	fatArchAlign  = 14         // 2^14 == 16384-byte alignment, matching lipo's default for 64-bit slices.
+	fatHeaderSize = 8
+	fatArchSize   = 20 // sizeof(struct fat_arch): 5 uint32 fields.
+)
+
+// isMachO64 reports whether data begins with a 64-bit Mach-O header. Fat (universal) binaries,
+// 32-bit Mach-O, and non-Mach-O files all return false: this package only fuses the 64-bit thin
+// binaries the darwin-amd64/darwin-arm64 Go toolchain produces.
+func isMachO64(data []byte) bool {
+	if len(data) < 32 {
+		return false
+	}
+	return binary.LittleEndian.Uint32(data[0:4]) == macho64Magic
+}
+
+// machoCPU reads the cputype/cpusubtype fields out of a 64-bit Mach-O header. Callers must check
+// isMachO64 first.
+func machoCPU(data []byte) (cpuType, cpuSubtype uint32) {
+	return binary.LittleEndian.Uint32(data[4:8]), binary.LittleEndian.Uint32(data[8:12])
+}
+
+// fuseMachO combines two thin 64-bit Mach-O executables for different architectures into one fat
+// (universal) binary, in the same format "lipo -create" produces: a big-endian fat_header
+// followed by one big-endian fat_arch per slice, then each slice's bytes at its recorded offset,
+// page-aligned.
+func fuseMachO(slices ...[]byte) ([]byte, error) {
+	if len(slices) < 2 {
+		return nil, fmt.Errorf("buildassets: fuseMachO: need at least 2 slices, got %v", len(slices))
+	}
+
+	align := uint64(1) << fatArchAlign
+	offset := uint64(fatHeaderSize + fatArchSize*len(slices))
+	offset = alignUp(offset, align)
+
+	type placedSlice struct {
+		cpuType, cpuSubtype uint32
+		offset              uint64
+		data                []byte
+	}
+	placed := make([]placedSlice, 0, len(slices))
+	for _, s := range slices {
+		if !isMachO64(s) {
+			return nil, fmt.Errorf("buildassets: fuseMachO: input is not a 64-bit Mach-O binary")
+		}
+		cpuType, cpuSubtype := machoCPU(s)
+		placed = append(placed, placedSlice{cpuType, cpuSubtype, offset, s})
+		offset = alignUp(offset+uint64(len(s)), align)
+	}
+
+	out := make([]byte, offset)
+	binary.BigEndian.PutUint32(out[0:4], fatMagic)
+	binary.BigEndian.PutUint32(out[4:8], uint32(len(placed)))
+
+	archOff := fatHeaderSize
+	for _, p := range placed {
+		binary.BigEndian.PutUint32(out[archOff:archOff+4], p.cpuType)
+		binary.BigEndian.PutUint32(out[archOff+4:archOff+8], p.cpuSubtype)
+		binary.BigEndian.PutUint32(out[archOff+8:archOff+12], uint32(p.offset))
+		binary.BigEndian.PutUint32(out[archOff+12:archOff+16], uint32(len(p.data)))
+		binary.BigEndian.PutUint32(out[archOff+16:archOff+20], fatArchAlign)
+		archOff += fatArchSize
+
+		copy(out[p.offset:], p.data)
+	}
+
+	return out, nil
+}
+
+// alignUp rounds n up to the nearest multiple of align, which must be a power of two.
+func alignUp(n, align uint64) uint64 {
+	return (n + align - 1) &^ (align - 1)
+}
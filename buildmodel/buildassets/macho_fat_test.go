@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// fakeMachO64 builds a minimal byte slice that looks like a 64-bit Mach-O header (enough for
+// isMachO64/machoCPU to parse) of the given total size, padded with a repeating byte so fused
+// slices are easy to tell apart in test failures.
+func fakeMachO64(cpuType, cpuSubtype uint32, size int, pad byte) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = pad
+	}
+	binary.LittleEndian.PutUint32(data[0:4], macho64Magic)
+	binary.LittleEndian.PutUint32(data[4:8], cpuType)
+	binary.LittleEndian.PutUint32(data[8:12], cpuSubtype)
+	return data
+}
+
+func TestIsMachO64(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"64-bit mach-o", fakeMachO64(1, 0, 40, 0), true},
+		{"too short", fakeMachO64(1, 0, 40, 0)[:16], false},
+		{"wrong magic", append([]byte{0, 0, 0, 0}, fakeMachO64(1, 0, 40, 0)[4:]...), false},
+		{"fat magic", func() []byte {
+			data := make([]byte, 40)
+			binary.BigEndian.PutUint32(data[0:4], fatMagic)
+			return data
+		}(), false},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isMachO64(tt.data); got != tt.want {
+				t.Errorf("isMachO64(%v bytes) = %v, want %v", len(tt.data), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuseMachO(t *testing.T) {
+	t.Run("too few slices", func(t *testing.T) {
+		if _, err := fuseMachO(fakeMachO64(1, 0, 40, 0)); err == nil {
+			t.Fatal("expected error for a single slice, got nil")
+		}
+	})
+
+	t.Run("non mach-o input", func(t *testing.T) {
+		if _, err := fuseMachO(fakeMachO64(1, 0, 40, 0), []byte("not mach-o")); err == nil {
+			t.Fatal("expected error for a non-Mach-O slice, got nil")
+		}
+	})
+
+	t.Run("fuses two slices", func(t *testing.T) {
+		amd64 := fakeMachO64(0x01000007, 3, 40, 0xaa)
+		arm64 := fakeMachO64(0x0100000c, 0, 64, 0xbb)
+
+		out, err := fuseMachO(amd64, arm64)
+		if err != nil {
+			t.Fatalf("fuseMachO: %v", err)
+		}
+
+		if got := binary.BigEndian.Uint32(out[0:4]); got != fatMagic {
+			t.Errorf("fat_header magic = %#x, want %#x", got, fatMagic)
+		}
+		if got := binary.BigEndian.Uint32(out[4:8]); got != 2 {
+			t.Errorf("fat_header nfat_arch = %v, want 2", got)
+		}
+
+		archOff := fatHeaderSize
+		wantSlices := [][]byte{amd64, arm64}
+		wantCPU := [][2]uint32{{0x01000007, 3}, {0x0100000c, 0}}
+		for i, slice := range wantSlices {
+			cpuType := binary.BigEndian.Uint32(out[archOff : archOff+4])
+			cpuSubtype := binary.BigEndian.Uint32(out[archOff+4 : archOff+8])
+			offset := binary.BigEndian.Uint32(out[archOff+8 : archOff+12])
+			size := binary.BigEndian.Uint32(out[archOff+12 : archOff+16])
+			align := binary.BigEndian.Uint32(out[archOff+16 : archOff+20])
+
+			if cpuType != wantCPU[i][0] || cpuSubtype != wantCPU[i][1] {
+				t.Errorf("slice %v: cputype/cpusubtype = %v/%v, want %v/%v", i, cpuType, cpuSubtype, wantCPU[i][0], wantCPU[i][1])
+			}
+			if int(size) != len(slice) {
+				t.Errorf("slice %v: fat_arch size = %v, want %v", i, size, len(slice))
+			}
+			if align != fatArchAlign {
+				t.Errorf("slice %v: fat_arch align = %v, want %v", i, align, fatArchAlign)
+			}
+			if uint64(offset)%(uint64(1)<<fatArchAlign) != 0 {
+				t.Errorf("slice %v: offset %v is not page-aligned", i, offset)
+			}
+			gotData := out[offset : uint64(offset)+uint64(len(slice))]
+			for j, b := range gotData {
+				if b != slice[j] {
+					t.Fatalf("slice %v: data mismatch at byte %v", i, j)
+				}
+			}
+
+			archOff += fatArchSize
+		}
+	})
+}
+
+func TestAlignUp(t *testing.T) {
+	tests := []struct {
+		n, align, want uint64
+	}{
+		{0, 16384, 0},
+		{1, 16384, 16384},
+		{16384, 16384, 16384},
+		{16385, 16384, 32768},
+		{40, 8, 40},
+		{41, 8, 48},
+	}
+	for _, tt := range tests {
+		if got := alignUp(tt.n, tt.align); got != tt.want {
+			t.Errorf("alignUp(%v, %v) = %v, want %v", tt.n, tt.align, got, tt.want)
+		}
+	}
+}
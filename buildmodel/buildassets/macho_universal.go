@@ -0,0 +1,186 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/microsoft/go-infra/buildmodel/dockerversions"
+)
+
+// darwinUniversalGOARCH is the synthetic GOARCH value used for the fused archive, matching how
+// Apple's own tooling refers to a universal (multi-slice) binary.
+const darwinUniversalGOARCH = "universal"
+
+// synthesizeDarwinUniversal looks for a darwin-amd64 and darwin-arm64 pair among arches and, if
+// both are present, fuses their archives into a darwin-universal archive via fuseArchiveMachO. If
+// either arch is missing, it returns (nil, nil): the caller should skip synthesis silently, since
+// this is only possible when a build produced both macOS arches.
+func synthesizeDarwinUniversal(b BuildResultsDirectoryInfo, arches []*dockerversions.Arch, archiveFileName map[*dockerversions.Arch]string) (*dockerversions.Arch, error) {
+	var amd64, arm64 *dockerversions.Arch
+	for _, a := range arches {
+		if a.Env.GOOS != "darwin" {
+			continue
+		}
+		switch a.Env.GOARCH {
+		case "amd64":
+			amd64 = a
+		case "arm64":
+			arm64 = a
+		}
+	}
+	if amd64 == nil || arm64 == nil {
+		return nil, nil
+	}
+
+	amd64Path := path.Join(b.ArtifactsDir, archiveFileName[amd64])
+	arm64Path := path.Join(b.ArtifactsDir, archiveFileName[arm64])
+
+	outName := universalArchiveName(archiveFileName[amd64])
+	outPath := path.Join(b.ArtifactsDir, outName)
+
+	sum, err := fuseArchiveMachO(amd64Path, arm64Path, outPath)
+	if err != nil {
+		return nil, fmt.Errorf("buildassets: synthesize darwin-universal: %w", err)
+	}
+
+	return &dockerversions.Arch{
+		URL:    b.DestinationURL + "/" + outName,
+		SHA256: sum,
+		Env: dockerversions.ArchEnv{
+			GOOS:   "darwin",
+			GOARCH: darwinUniversalGOARCH,
+		},
+	}, nil
+}
+
+// universalArchiveName rewrites a "...darwin-amd64.tar.gz" archive name to "...darwin-universal.tar.gz".
+func universalArchiveName(amd64ArchiveName string) string {
+	return strings.Replace(amd64ArchiveName, "darwin-amd64", "darwin-"+darwinUniversalGOARCH, 1)
+}
+
+// fuseArchiveMachO reads the amd64 and arm64 archives at the given paths, fuses every executable
+// that appears (by path) in both into a single Mach-O fat binary, writes the result as a new
+// .tar.gz at outPath alongside a fresh outPath+".sha256", and returns the new archive's checksum.
+//
+// Entries that appear only in the amd64 archive, or whose content isn't a 64-bit Mach-O binary in
+// both archives, are passed through unchanged from the amd64 archive: this handles READMEs, the `go`
+// wrapper scripts, and similar non-executable files that don't need (or can't have) an arm64 slice.
+// Entries that appear only in the arm64 archive are not included in the output.
+func fuseArchiveMachO(amd64ArchivePath, arm64ArchivePath, outPath string) (string, error) {
+	amd64Entries, amd64Order, err := readTarGzEntries(amd64ArchivePath)
+	if err != nil {
+		return "", fmt.Errorf("reading %v: %w", amd64ArchivePath, err)
+	}
+	arm64Entries, _, err := readTarGzEntries(arm64ArchivePath)
+	if err != nil {
+		return "", fmt.Errorf("reading %v: %w", arm64ArchivePath, err)
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	h := sha256.New()
+	mw := io.MultiWriter(out, h)
+
+	gz := gzip.NewWriter(mw)
+	tw := tar.NewWriter(gz)
+
+	for _, name := range amd64Order {
+		hdr, data := amd64Entries[name].header, amd64Entries[name].data
+		if arm64, ok := arm64Entries[name]; ok && isMachO64(data) && isMachO64(arm64.data) {
+			fused, err := fuseMachO(data, arm64.data)
+			if err != nil {
+				return "", fmt.Errorf("fusing %v: %w", name, err)
+			}
+			data = fused
+		}
+
+		newHdr := *hdr
+		newHdr.Size = int64(len(data))
+		if err := tw.WriteHeader(&newHdr); err != nil {
+			return "", err
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := tw.Write(data); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+
+	sum := fmt.Sprintf("%x", h.Sum(nil))
+	if err := os.WriteFile(outPath+checksumSuffix, []byte(sum+"  "+path.Base(outPath)+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("writing checksum file: %w", err)
+	}
+	return sum, nil
+}
+
+// tarEntry holds one archive member's header and fully-read content.
+type tarEntry struct {
+	header *tar.Header
+	data   []byte
+}
+
+// readTarGzEntries reads every entry of a .tar.gz archive into memory, returning entries keyed by
+// path plus the original entry order (needed to reproduce the archive deterministically).
+func readTarGzEntries(archivePath string) (map[string]tarEntry, []string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gz.Close()
+
+	entries := make(map[string]tarEntry)
+	var order []string
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var data []byte
+		if hdr.Typeflag == tar.TypeReg {
+			var buf bytes.Buffer
+			if _, err := io.Copy(&buf, tr); err != nil {
+				return nil, nil, err
+			}
+			data = buf.Bytes()
+		}
+
+		hdrCopy := *hdr
+		entries[hdr.Name] = tarEntry{header: &hdrCopy, data: data}
+		order = append(order, hdr.Name)
+	}
+
+	return entries, order, nil
+}
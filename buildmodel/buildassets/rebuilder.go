@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// ScriptRebuilder is the default Rebuilder implementation. It checks out sourceCommit into a fresh
+// clone of SourceRepoURL and runs the release branch's own archiving scripts, the same ones that
+// produce the artifacts a CI pipeline would upload. This keeps the "build" step here identical to
+// the real thing rather than reimplementing any part of it.
+type ScriptRebuilder struct {
+	// SourceRepoURL is the Git repo to clone, e.g. a local path or "https://github.com/microsoft/go".
+	SourceRepoURL string
+	// BuildScriptRelativePath is the path, relative to the cloned source tree, of the script that
+	// produces the release archives (e.g. "eng/_util/cibuild-gorelease.sh" on the branch).
+	BuildScriptRelativePath string
+}
+
+// Rebuild implements Rebuilder by cloning SourceRepoURL at sourceCommit into a scratch directory
+// under outDir, then running the release branch's build script with GOROOT_BOOTSTRAP pointed at
+// toolchain's GOROOT (if set) so the archives land in outDir.
+func (r ScriptRebuilder) Rebuild(ctx context.Context, sourceCommit, outDir, toolchain string) error {
+	srcDir := filepath.Join(outDir, "src")
+	if err := r.cloneAt(ctx, srcDir, sourceCommit); err != nil {
+		return fmt.Errorf("buildassets: rebuild: clone failed: %w", err)
+	}
+
+	scriptPath := filepath.Join(srcDir, r.BuildScriptRelativePath)
+	cmd := exec.CommandContext(ctx, scriptPath, outDir)
+	cmd.Dir = srcDir
+	cmd.Env = os.Environ()
+	if toolchain != "" {
+		cmd.Env = append(cmd.Env, "GOROOT_BOOTSTRAP="+bootstrapGOROOT(toolchain))
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("buildassets: rebuild: build script failed: %w", err)
+	}
+	return nil
+}
+
+// bootstrapGOROOT derives the GOROOT directory from the path to a bootstrap "go" binary, e.g.
+// "/opt/go1.20/bin/go" -> "/opt/go1.20". Every caller of Rebuild documents its toolchain parameter
+// as the path to the go binary (VerifyReproducibilityOptions.BootstrapToolchain, reprotest's
+// -bootstrap-toolchain flag), but the build scripts this shells out to look for
+// "$GOROOT_BOOTSTRAP/bin/go", so the env var itself needs the GOROOT directory, not the binary.
+func bootstrapGOROOT(toolchain string) string {
+	return filepath.Dir(filepath.Dir(toolchain))
+}
+
+// cloneAt performs a shallow clone of r.SourceRepoURL at commit into dir.
+func (r ScriptRebuilder) cloneAt(ctx context.Context, dir, commit string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	init := exec.CommandContext(ctx, "git", "init", dir)
+	if err := init.Run(); err != nil {
+		return err
+	}
+	fetch := exec.CommandContext(ctx, "git", "-C", dir, "fetch", "--depth=1", r.SourceRepoURL, commit)
+	if err := fetch.Run(); err != nil {
+		return err
+	}
+	checkout := exec.CommandContext(ctx, "git", "-C", dir, "checkout", "FETCH_HEAD")
+	return checkout.Run()
+}
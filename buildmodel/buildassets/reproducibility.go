@@ -0,0 +1,392 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/microsoft/go-infra/buildmodel/dockerversions"
+)
+
+// FileStatus describes the result of comparing one rebuilt archive against the archive recorded in
+// a BuildAssets summary.
+type FileStatus string
+
+const (
+	// StatusMatched means the rebuilt archive's checksum is identical to the recorded one.
+	StatusMatched FileStatus = "Matched"
+	// StatusMismatched means the rebuild produced an archive, but its checksum differs.
+	StatusMismatched FileStatus = "Mismatched"
+	// StatusMissing means the original BuildAssets entry has no corresponding rebuilt archive.
+	StatusMissing FileStatus = "Missing"
+	// StatusBuildError means the rebuild itself failed before an archive could be produced.
+	StatusBuildError FileStatus = "BuildError"
+)
+
+// ArchReproResult is the reproducibility result for a single arch entry in a BuildAssets summary.
+type ArchReproResult struct {
+	// Name identifies the arch, e.g. "linux-amd64".
+	Name string `json:"name"`
+	// Status is the overall comparison result for this arch.
+	Status FileStatus `json:"status"`
+	// RecordedSHA256 is the checksum that was published in the original BuildAssets.
+	RecordedSHA256 string `json:"recordedSha256"`
+	// RebuiltSHA256 is the checksum produced by the independent rebuild, empty on BuildError.
+	RebuiltSHA256 string `json:"rebuiltSha256,omitempty"`
+	// EntryDiff lists the per-file differences found inside the archive, populated only when
+	// Status is StatusMismatched and the original archive is available locally for comparison.
+	EntryDiff []ArchiveEntryDiff `json:"entryDiff,omitempty"`
+	// Error is the rebuild error message, populated only when Status is StatusBuildError.
+	Error string `json:"error,omitempty"`
+	// Duration is how long the rebuild of this arch took.
+	Duration time.Duration `json:"duration"`
+}
+
+// ArchiveEntryDiff describes a single file inside an archive whose content diverged between the
+// recorded archive and the rebuilt one.
+type ArchiveEntryDiff struct {
+	// Path is the file's path inside the archive.
+	Path string `json:"path"`
+	// RecordedSHA256 is the checksum of the file as found in the original archive, if available.
+	RecordedSHA256 string `json:"recordedSha256,omitempty"`
+	// RebuiltSHA256 is the checksum of the file as found in the rebuilt archive, if available.
+	RebuiltSHA256 string `json:"rebuiltSha256,omitempty"`
+}
+
+// ReproReport is a structured, JSON-serializable record of a reproducibility verification run
+// against a BuildAssets summary. Its shape is similar to Go's own gorebuild report: one entry per
+// arch plus enough toolchain/timing context to explain a mismatch after the fact.
+type ReproReport struct {
+	// Version is the BuildAssets.Version that was verified.
+	Version string `json:"version"`
+	// Branch is the BuildAssets.Branch that was verified.
+	Branch string `json:"branch"`
+	// Toolchain identifies the Go toolchain used to perform the rebuild: either the bootstrap
+	// toolchain path (in -full mode) or "go (host)" if the host's go was used.
+	Toolchain string `json:"toolchain"`
+	// Full indicates whether the rebuild used the caller-supplied bootstrap toolchain
+	// (VerifyReproducibilityOptions.BootstrapToolchain) rather than the host "go".
+	Full bool `json:"full"`
+	// StartTime is when the verification run began.
+	StartTime time.Time `json:"startTime"`
+	// Duration is the total wall-clock time of the verification run.
+	Duration time.Duration `json:"duration"`
+	// Arches holds one result per arch in the original BuildAssets.
+	Arches []ArchReproResult `json:"arches"`
+}
+
+// AllMatched reports whether every arch in the report reproduced successfully.
+func (r *ReproReport) AllMatched() bool {
+	for _, a := range r.Arches {
+		if a.Status != StatusMatched {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteJSON writes the report to w as indented JSON, matching the format BuildAssets files use.
+func (r *ReproReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Rebuilder abstracts "give me a fresh source tree at a given commit and produce build artifacts
+// in a given directory." The default implementation shells out to the release branch's build
+// scripts, but this is an interface so tests and alternative environments (e.g. a hosted build
+// service) can be substituted.
+type Rebuilder interface {
+	// Rebuild checks out sourceCommit into outDir/"src" and builds it, writing archives and
+	// checksums directly into outDir in the same layout CreateSummary expects from
+	// BuildResultsDirectoryInfo. Fixing the source tree's location relative to outDir lets
+	// verifyArch point a BuildResultsDirectoryInfo at the rebuild output and reuse CreateSummary's
+	// discovery logic instead of guessing archive names itself.
+	// toolchain is the path to the go binary to build with; if empty, the host's "go" is used.
+	Rebuild(ctx context.Context, sourceCommit, outDir, toolchain string) error
+}
+
+// VerifyReproducibilityOptions configures VerifyReproducibility.
+type VerifyReproducibilityOptions struct {
+	// Rebuilder produces independent build output for comparison. Required.
+	Rebuilder Rebuilder
+	// WorkDir is a scratch directory VerifyReproducibility may use and clean up per arch. A
+	// temporary directory is created under it for each arch's rebuild.
+	WorkDir string
+	// OriginalArtifactsDir, if set, is searched for the original archives named in assets so a
+	// mismatch can be explained with a per-entry diff. If empty, mismatches are reported without
+	// EntryDiff.
+	OriginalArtifactsDir string
+	// Full, if true, rebuilds using BootstrapToolchain instead of the host "go". The toolchain is
+	// not looked up from the source tree: whatever path BootstrapToolchain holds is used as is.
+	Full bool
+	// BootstrapToolchain is the path to the bootstrap go binary to use when Full is true.
+	BootstrapToolchain string
+	// MaxParallel bounds how many arches are rebuilt concurrently. Defaults to 1 if <= 0.
+	MaxParallel int
+}
+
+// VerifyReproducibility independently re-runs the Microsoft Go build that produced assets in a
+// scratch work directory per arch, then compares the resulting archives against the SHA256s
+// recorded in assets, reporting one ArchReproResult per arch regardless of whether individual
+// arches fail to rebuild.
+func VerifyReproducibility(ctx context.Context, assets *BuildAssets, sourceCommit string, opts VerifyReproducibilityOptions) (*ReproReport, error) {
+	if opts.Rebuilder == nil {
+		return nil, fmt.Errorf("buildassets: VerifyReproducibility: Rebuilder must be set")
+	}
+	maxParallel := opts.MaxParallel
+	if maxParallel <= 0 {
+		maxParallel = 1
+	}
+
+	toolchain := "go (host)"
+	bootstrap := ""
+	if opts.Full {
+		toolchain = opts.BootstrapToolchain
+		bootstrap = opts.BootstrapToolchain
+	}
+
+	start := time.Now()
+	report := &ReproReport{
+		Version:   assets.Version,
+		Branch:    assets.Branch,
+		Toolchain: toolchain,
+		Full:      opts.Full,
+		StartTime: start,
+		Arches:    make([]ArchReproResult, len(assets.Arches)),
+	}
+
+	sem := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+	for i, a := range assets.Arches {
+		i, a := i, a
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			report.Arches[i] = verifyArch(ctx, opts, sourceCommit, bootstrap, assets.Branch, a)
+		}()
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// verifyArch rebuilds and compares a single arch entry. It never returns an error: build failures
+// are recorded as StatusBuildError inside the returned ArchReproResult so one bad arch doesn't
+// abort the rest of the verification run.
+func verifyArch(ctx context.Context, opts VerifyReproducibilityOptions, sourceCommit, bootstrapToolchain, branch string, a *dockerversions.Arch) ArchReproResult {
+	name := a.Env.GOOS + "-" + a.Env.GOARCH
+	result := ArchReproResult{
+		Name:           name,
+		RecordedSHA256: a.SHA256,
+	}
+
+	start := time.Now()
+	outDir, err := os.MkdirTemp(opts.WorkDir, "reprobuild-"+name+"-")
+	if err != nil {
+		result.Status = StatusBuildError
+		result.Error = fmt.Sprintf("unable to create scratch dir: %v", err)
+		result.Duration = time.Since(start)
+		return result
+	}
+	defer os.RemoveAll(outDir)
+
+	if err := opts.Rebuilder.Rebuild(ctx, sourceCommit, outDir, bootstrapToolchain); err != nil {
+		result.Status = StatusBuildError
+		result.Error = err.Error()
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// Reuse the same discovery logic CreateSummary uses on a real build's output, instead of
+	// guessing the rebuilt archive's name: this way a rebuild that stages output under a
+	// differently-named or additional file is still found and matched by GOOS/GOARCH.
+	rebuilt, err := BuildResultsDirectoryInfo{
+		SourceDir:    filepath.Join(outDir, "src"),
+		ArtifactsDir: outDir,
+		Branch:       branch,
+	}.CreateSummary()
+	if err != nil {
+		result.Status = StatusBuildError
+		result.Error = fmt.Sprintf("unable to discover rebuilt artifacts: %v", err)
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	rebuiltArch := findArchByEnv(rebuilt.Arches, a.Env)
+	if rebuiltArch == nil {
+		result.Status = StatusMissing
+		result.Duration = time.Since(start)
+		return result
+	}
+	result.RebuiltSHA256 = rebuiltArch.SHA256
+
+	if rebuiltArch.SHA256 == a.SHA256 {
+		result.Status = StatusMatched
+	} else {
+		result.Status = StatusMismatched
+		if opts.OriginalArtifactsDir != "" {
+			originalPath := filepath.Join(opts.OriginalArtifactsDir, path.Base(a.URL))
+			rebuiltPath := filepath.Join(outDir, path.Base(rebuiltArch.URL))
+			if diff, diffErr := diffArchiveEntries(originalPath, rebuiltPath); diffErr == nil {
+				result.EntryDiff = diff
+			}
+		}
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// findArchByEnv returns the arch in arches whose Env matches env, or nil if none does.
+func findArchByEnv(arches []*dockerversions.Arch, env dockerversions.ArchEnv) *dockerversions.Arch {
+	for _, a := range arches {
+		if a.Env == env {
+			return a
+		}
+	}
+	return nil
+}
+
+// diffArchiveEntries compares the file lists and per-entry checksums of two archives so maintainers
+// can see which object files diverged on a reproducibility mismatch.
+func diffArchiveEntries(recordedArchive, rebuiltArchive string) ([]ArchiveEntryDiff, error) {
+	recorded, err := readArchiveEntryChecksums(recordedArchive)
+	if err != nil {
+		return nil, err
+	}
+	rebuilt, err := readArchiveEntryChecksums(rebuiltArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []ArchiveEntryDiff
+	for entryPath, recordedSum := range recorded {
+		if rebuiltSum := rebuilt[entryPath]; rebuiltSum != recordedSum {
+			diffs = append(diffs, ArchiveEntryDiff{
+				Path:           entryPath,
+				RecordedSHA256: recordedSum,
+				RebuiltSHA256:  rebuiltSum,
+			})
+		}
+	}
+	for entryPath, rebuiltSum := range rebuilt {
+		if _, ok := recorded[entryPath]; !ok {
+			diffs = append(diffs, ArchiveEntryDiff{Path: entryPath, RebuiltSHA256: rebuiltSum})
+		}
+	}
+	return diffs, nil
+}
+
+// readArchiveEntryChecksums opens a .tar.gz or .zip archive and returns the sha256 of each regular
+// file entry, keyed by the entry's path inside the archive.
+func readArchiveEntryChecksums(archivePath string) (map[string]string, error) {
+	switch {
+	case hasSuffixFold(archivePath, ".tar.gz"):
+		return readTarGzChecksums(archivePath)
+	case hasSuffixFold(archivePath, ".zip"):
+		return readZipChecksums(archivePath)
+	default:
+		return nil, fmt.Errorf("buildassets: unsupported archive format: %v", archivePath)
+	}
+}
+
+func readTarGzChecksums(archivePath string) (map[string]string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	sums := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, tr); err != nil {
+			return nil, err
+		}
+		sums[hdr.Name] = fmt.Sprintf("%x", h.Sum(nil))
+	}
+	return sums, nil
+}
+
+func readZipChecksums(archivePath string) (map[string]string, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	sums := make(map[string]string)
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, copyErr := io.Copy(h, rc)
+		rc.Close()
+		if copyErr != nil {
+			return nil, copyErr
+		}
+		sums[f.Name] = fmt.Sprintf("%x", h.Sum(nil))
+	}
+	return sums, nil
+}
+
+// hasSuffixFold reports whether s ends with suffix, ignoring ASCII case. (discoverArches matches
+// archiveSuffixes case-sensitively.)
+func hasSuffixFold(s, suffix string) bool {
+	if len(s) < len(suffix) {
+		return false
+	}
+	tail := s[len(s)-len(suffix):]
+	for i := 0; i < len(suffix); i++ {
+		c1, c2 := tail[i], suffix[i]
+		if 'A' <= c1 && c1 <= 'Z' {
+			c1 += 'a' - 'A'
+		}
+		if 'A' <= c2 && c2 <= 'Z' {
+			c2 += 'a' - 'A'
+		}
+		if c1 != c2 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,314 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+
+	"github.com/microsoft/go-infra/buildmodel/dockerversions"
+)
+
+// Signer produces a detached signature over a byte slice. Implementations are expected to be
+// cheap to construct and are not required to be safe for concurrent use unless documented
+// otherwise.
+type Signer interface {
+	// Name identifies the signing scheme, e.g. "gpg" or "cosign". It is used as the key under
+	// BuildAssets.Signatures.
+	Name() string
+	// Sign returns a detached signature over data.
+	Sign(data []byte) ([]byte, error)
+}
+
+// Verifier checks a detached signature produced by a Signer against a trust root configured on the
+// Verifier implementation.
+type Verifier interface {
+	// Name identifies the signing scheme this Verifier checks, matching the Signer.Name that
+	// produced the signature.
+	Name() string
+	// Verify returns an error if signature is not a valid signature over data.
+	Verify(data, signature []byte) error
+}
+
+// canonicalSigningBytes returns the bytes that SignSummary and VerifySummary sign: the manifest
+// JSON with the Signatures field cleared, so re-signing doesn't change what was signed.
+func canonicalSigningBytes(b BuildAssets) ([]byte, error) {
+	b.Signatures = nil
+	return json.Marshal(b)
+}
+
+// SignSummary signs the manifest (excluding the Signatures field itself) with signer and stores
+// the resulting detached signature under Signatures[signer.Name()].
+func (b *BuildAssets) SignSummary(signer Signer) error {
+	data, err := canonicalSigningBytes(*b)
+	if err != nil {
+		return fmt.Errorf("buildassets: SignSummary: %w", err)
+	}
+	sig, err := signer.Sign(data)
+	if err != nil {
+		return fmt.Errorf("buildassets: SignSummary: %w", err)
+	}
+	if b.Signatures == nil {
+		b.Signatures = make(map[string]string)
+	}
+	b.Signatures[signer.Name()] = base64.StdEncoding.EncodeToString(sig)
+	return nil
+}
+
+// VerifySummary checks the manifest's signature under Signatures[verifier.Name()] against
+// verifier's trust root. It returns an error if the signature is missing, malformed, or invalid.
+func (b BuildAssets) VerifySummary(verifier Verifier) error {
+	encoded, ok := b.Signatures[verifier.Name()]
+	if !ok {
+		return fmt.Errorf("buildassets: VerifySummary: no %q signature present", verifier.Name())
+	}
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("buildassets: VerifySummary: unable to decode %q signature: %w", verifier.Name(), err)
+	}
+	data, err := canonicalSigningBytes(b)
+	if err != nil {
+		return fmt.Errorf("buildassets: VerifySummary: %w", err)
+	}
+	if err := verifier.Verify(data, sig); err != nil {
+		return fmt.Errorf("buildassets: VerifySummary: %q signature invalid: %w", verifier.Name(), err)
+	}
+	return nil
+}
+
+// VerifyArchSignatures checks every arch's dockerversions.Arch.Signature against verifier. The
+// signed payload depends on the arch's SignatureKind: SignatureKindSHA256 signs the recorded
+// SHA256 checksum string, while SignatureKindArchive signs the archive's raw bytes, which must be
+// read from source. Auto-update should call this and refuse to proceed if it returns an error, so
+// a missing or forged signature never reaches Go Docker.
+//
+// source may be nil only if every arch uses SignatureKindSHA256 (what CreateSummary records for a
+// ".sha256.sig" file); it's required to verify any SignatureKindArchive signature, since that payload
+// isn't part of the manifest itself.
+func (b BuildAssets) VerifyArchSignatures(verifier Verifier, source ArtifactSource) error {
+	for _, a := range b.Arches {
+		if a.Signature == "" {
+			return fmt.Errorf("buildassets: VerifyArchSignatures: arch %v has no signature", a.URL)
+		}
+		sig, err := base64.StdEncoding.DecodeString(a.Signature)
+		if err != nil {
+			return fmt.Errorf("buildassets: VerifyArchSignatures: arch %v: unable to decode signature: %w", a.URL, err)
+		}
+
+		var payload []byte
+		switch a.SignatureKind {
+		case dockerversions.SignatureKindArchive:
+			if source == nil {
+				return fmt.Errorf("buildassets: VerifyArchSignatures: arch %v: signature is over the archive itself, but no ArtifactSource was provided to read it from", a.URL)
+			}
+			payload, err = readAll(source, path.Base(a.URL))
+			if err != nil {
+				return fmt.Errorf("buildassets: VerifyArchSignatures: arch %v: unable to read archive: %w", a.URL, err)
+			}
+		case dockerversions.SignatureKindSHA256, "":
+			// "" covers signatures discovered before SignatureKind existed, which were always
+			// verified against the SHA256 string.
+			payload = []byte(a.SHA256)
+		default:
+			return fmt.Errorf("buildassets: VerifyArchSignatures: arch %v: unknown signature kind %q", a.URL, a.SignatureKind)
+		}
+
+		if err := verifier.Verify(payload, sig); err != nil {
+			return fmt.Errorf("buildassets: VerifyArchSignatures: arch %v: %w", a.URL, err)
+		}
+	}
+	return nil
+}
+
+// GPGSigner signs data using a detached ASCII-armored GPG signature. The private key material is
+// read from the environment variable named KeyEnvVar, mirroring the "-signer key-envvar" pattern
+// used by other Go-ecosystem CI tooling to avoid ever writing key material to disk outside of a
+// short-lived temp file.
+type GPGSigner struct {
+	// KeyEnvVar is the name of the environment variable holding the ASCII-armored private key to
+	// sign with.
+	KeyEnvVar string
+}
+
+// Name implements Signer.
+func (s GPGSigner) Name() string { return "gpg" }
+
+// Sign implements Signer by importing the key from the configured environment variable into a
+// scratch GPG home directory and running "gpg --detach-sign" against data.
+func (s GPGSigner) Sign(data []byte) ([]byte, error) {
+	key := os.Getenv(s.KeyEnvVar)
+	if key == "" {
+		return nil, fmt.Errorf("gpg signer: environment variable %q is not set", s.KeyEnvVar)
+	}
+
+	gnupgHome, err := os.MkdirTemp("", "buildassets-gnupg-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(gnupgHome)
+
+	runGPG := func(args []string, stdin []byte) ([]byte, error) {
+		cmd := exec.Command("gpg", append([]string{"--batch", "--yes", "--homedir", gnupgHome}, args...)...)
+		cmd.Stdin = bytes.NewReader(stdin)
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("gpg %v: %w", args, err)
+		}
+		return out.Bytes(), nil
+	}
+
+	if _, err := runGPG([]string{"--import"}, []byte(key)); err != nil {
+		return nil, fmt.Errorf("gpg signer: import key: %w", err)
+	}
+
+	sig, err := runGPG([]string{"--armor", "--detach-sign"}, data)
+	if err != nil {
+		return nil, fmt.Errorf("gpg signer: sign: %w", err)
+	}
+	return sig, nil
+}
+
+// GPGVerifier verifies a detached ASCII-armored GPG signature against a configured public
+// keyring, acting as the trust root.
+type GPGVerifier struct {
+	// TrustedKeyringPath is the path to a GPG public keyring file containing the keys this
+	// Verifier trusts.
+	TrustedKeyringPath string
+}
+
+// Name implements Verifier.
+func (v GPGVerifier) Name() string { return "gpg" }
+
+// Verify implements Verifier by importing TrustedKeyringPath into a scratch GPG home directory and
+// running "gpg --verify" against data and signature.
+func (v GPGVerifier) Verify(data, signature []byte) error {
+	gnupgHome, err := os.MkdirTemp("", "buildassets-gnupg-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(gnupgHome)
+
+	importCmd := exec.Command("gpg", "--batch", "--homedir", gnupgHome, "--import", v.TrustedKeyringPath)
+	importCmd.Stderr = os.Stderr
+	if err := importCmd.Run(); err != nil {
+		return fmt.Errorf("gpg verifier: import trusted keyring: %w", err)
+	}
+
+	sigFile, err := os.CreateTemp(gnupgHome, "signature-*.sig")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(sigFile.Name())
+	if _, err := sigFile.Write(signature); err != nil {
+		sigFile.Close()
+		return err
+	}
+	sigFile.Close()
+
+	dataFile, err := os.CreateTemp(gnupgHome, "data-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dataFile.Name())
+	if _, err := dataFile.Write(data); err != nil {
+		dataFile.Close()
+		return err
+	}
+	dataFile.Close()
+
+	verifyCmd := exec.Command("gpg", "--batch", "--homedir", gnupgHome, "--verify", sigFile.Name(), dataFile.Name())
+	verifyCmd.Stderr = os.Stderr
+	if err := verifyCmd.Run(); err != nil {
+		return fmt.Errorf("gpg verifier: signature did not verify: %w", err)
+	}
+	return nil
+}
+
+// CosignSigner produces a keyless sigstore signature using the "cosign" CLI's OIDC-based keyless
+// flow, rather than a long-lived private key.
+type CosignSigner struct{}
+
+// Name implements Signer.
+func (CosignSigner) Name() string { return "cosign" }
+
+// Sign implements Signer by running "cosign sign-blob" in keyless mode, returning the signature
+// bundle cosign produces.
+func (CosignSigner) Sign(data []byte) ([]byte, error) {
+	dataFile, err := os.CreateTemp("", "buildassets-cosign-data-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(dataFile.Name())
+	if _, err := dataFile.Write(data); err != nil {
+		dataFile.Close()
+		return nil, err
+	}
+	dataFile.Close()
+
+	cmd := exec.Command("cosign", "sign-blob", "--yes", dataFile.Name())
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("cosign signer: %w", err)
+	}
+	return bytes.TrimSpace(out.Bytes()), nil
+}
+
+// CosignVerifier verifies a keyless sigstore signature against the public Fulcio/Rekor trust
+// root, restricted to a specific signing identity.
+type CosignVerifier struct {
+	// CertificateIdentityRegexp restricts verification to signatures whose certificate identity
+	// (e.g. a GitHub Actions OIDC subject) matches this regexp.
+	CertificateIdentityRegexp string
+	// CertificateOIDCIssuer restricts verification to signatures issued by this OIDC issuer.
+	CertificateOIDCIssuer string
+}
+
+// Name implements Verifier.
+func (CosignVerifier) Name() string { return "cosign" }
+
+// Verify implements Verifier by running "cosign verify-blob" against data and signature, checking
+// the signing identity against the configured trust root.
+func (v CosignVerifier) Verify(data, signature []byte) error {
+	dataFile, err := os.CreateTemp("", "buildassets-cosign-data-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dataFile.Name())
+	if _, err := dataFile.Write(data); err != nil {
+		dataFile.Close()
+		return err
+	}
+	dataFile.Close()
+
+	sigFile, err := os.CreateTemp("", "buildassets-cosign-sig-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(sigFile.Name())
+	if _, err := sigFile.Write(signature); err != nil {
+		sigFile.Close()
+		return err
+	}
+	sigFile.Close()
+
+	cmd := exec.Command("cosign", "verify-blob",
+		"--certificate-identity-regexp", v.CertificateIdentityRegexp,
+		"--certificate-oidc-issuer", v.CertificateOIDCIssuer,
+		"--signature", sigFile.Name(),
+		dataFile.Name())
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cosign verifier: signature did not verify: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import (
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// PrereleaseKind identifies what kind of prerelease a Go version tag names, matching the suffixes
+// Go itself uses in release tags ("go1.21rc1", "go1.21beta1").
+type PrereleaseKind string
+
+const (
+	// PrereleaseNone means the version is a final release, not a prerelease.
+	PrereleaseNone PrereleaseKind = ""
+	PrereleaseBeta PrereleaseKind = "beta"
+	PrereleaseRC   PrereleaseKind = "rc"
+)
+
+// Track categorizes a build for the purpose of selecting which Go Docker tag family (and target
+// branch) it belongs to. It's intentionally coarser than PrereleaseKind: boringcrypto and fips
+// builds are their own tracks regardless of what version of upstream Go they're based on.
+type Track string
+
+const (
+	TrackStable       Track = "stable"
+	TrackBeta         Track = "beta"
+	TrackRC           Track = "rc"
+	TrackBoringCrypto Track = "boringcrypto"
+	TrackFIPS         Track = "fips"
+)
+
+// versionTagPattern matches Go's own release tag format, e.g. "go1.21", "go1.21.3", "go1.22rc1".
+var versionTagPattern = regexp.MustCompile(`^go(\d+\.\d+)(\.\d+|)((beta|rc)(\d+))?$`)
+
+// VersionInfo is richer version metadata about a Go source tree than the raw "major.minor.patch-
+// revision" string BuildAssets.Version carries, extracted by scanning the source tree the same way
+// getVersion does, plus branch inspection for boringcrypto/fips detection.
+type VersionInfo struct {
+	// MajorMinor is the "major.minor" portion of the version, e.g. "1.21". Empty if the source
+	// tree's VERSION file is missing or doesn't match Go's tag format (e.g. a "main" branch
+	// snapshot with no VERSION file yet).
+	MajorMinor string
+	// Patch is the patch number, e.g. "3" for go1.21.3, or "" for go1.21 (patch 0 implied).
+	Patch string
+	// PrereleaseKind is PrereleaseBeta or PrereleaseRC, or PrereleaseNone for a final release.
+	PrereleaseKind PrereleaseKind
+	// PrereleaseNum is the number following PrereleaseKind, e.g. "1" for "rc1". Empty when
+	// PrereleaseKind is PrereleaseNone.
+	PrereleaseNum string
+	// Revision is Microsoft's own revision counter, read from MICROSOFT_REVISION.
+	Revision string
+	// Track categorizes the release for Go Docker tag/branch selection.
+	Track Track
+	// CommitHash is the short Git commit hash of the source tree, if it could be determined.
+	CommitHash string
+}
+
+// Prerelease returns the combined prerelease suffix, e.g. "rc1" or "beta2", or "" for a final
+// release. This is the form stored in BuildAssets.Prerelease.
+func (v VersionInfo) Prerelease() string {
+	if v.PrereleaseKind == PrereleaseNone {
+		return ""
+	}
+	return string(v.PrereleaseKind) + v.PrereleaseNum
+}
+
+// parseVersionTag parses a Go version tag like "go1.21.3" or "go1.22rc1" into a VersionInfo. It
+// returns ok=false if tag doesn't match Go's tag format at all, which happens for the "main"
+// default used when a source tree has no VERSION file.
+func parseVersionTag(tag string) (v VersionInfo, ok bool) {
+	m := versionTagPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return VersionInfo{}, false
+	}
+	v.MajorMinor = m[1]
+	v.Patch = strings.TrimPrefix(m[2], ".")
+	if m[4] != "" {
+		v.PrereleaseKind = PrereleaseKind(m[4])
+		v.PrereleaseNum = m[5]
+	}
+	return v, true
+}
+
+// getVersionInfo scans sourceDir the same way getVersion does, then parses and enriches the result
+// into a VersionInfo. It also returns the legacy "major.minor[.patch][prerelease]" version string
+// (VERSION file content with the "go" prefix trimmed, or "main" if the file doesn't exist) so
+// callers can keep building BuildAssets.Version exactly as before.
+func getVersionInfo(sourceDir, branch string) (VersionInfo, string, error) {
+	tag, err := getVersion(path.Join(sourceDir, "VERSION"), "main")
+	if err != nil {
+		return VersionInfo{}, "", err
+	}
+	revision, err := getVersion(path.Join(sourceDir, "MICROSOFT_REVISION"), "1")
+	if err != nil {
+		return VersionInfo{}, "", err
+	}
+
+	// If tag doesn't match Go's tag format (e.g. "main"), leave the parsed fields empty rather
+	// than erroring: this is the normal case for a dev branch build with no VERSION file yet.
+	v, _ := parseVersionTag(tag)
+	v.Revision = revision
+	v.Track = detectTrack(branch, v)
+	v.CommitHash = readShortCommitHash(sourceDir)
+
+	return v, strings.TrimPrefix(tag, "go"), nil
+}
+
+// detectTrack categorizes a build into a Track based on its branch name and parsed prerelease
+// info. boringcrypto and fips branches are their own tracks regardless of prerelease status,
+// since Go Docker treats them as entirely separate tag families.
+func detectTrack(branch string, v VersionInfo) Track {
+	switch {
+	case branch == "dev.boringcrypto" || strings.HasPrefix(branch, "dev.boringcrypto/"):
+		return TrackBoringCrypto
+	case strings.HasPrefix(branch, "dev/official/") && strings.Contains(branch, "-fips-"):
+		return TrackFIPS
+	case v.PrereleaseKind == PrereleaseRC:
+		return TrackRC
+	case v.PrereleaseKind == PrereleaseBeta:
+		return TrackBeta
+	default:
+		return TrackStable
+	}
+}
+
+// readShortCommitHash returns the short Git commit hash of sourceDir's HEAD, or "" if it can't be
+// determined (not a Git repo, git not installed, etc.). This is best-effort: a build result
+// summary shouldn't fail just because commit info isn't available.
+func readShortCommitHash(sourceDir string) string {
+	out, err := exec.Command("git", "-C", sourceDir, "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
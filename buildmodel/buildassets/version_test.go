@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package buildassets
+
+import "testing"
+
+func TestParseVersionTag(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   VersionInfo
+		wantOK bool
+	}{
+		{"go1.21.3", VersionInfo{MajorMinor: "1.21", Patch: "3"}, true},
+		{"go1.21", VersionInfo{MajorMinor: "1.21", Patch: ""}, true},
+		{"go1.22rc1", VersionInfo{MajorMinor: "1.22", PrereleaseKind: PrereleaseRC, PrereleaseNum: "1"}, true},
+		{"go1.22beta2", VersionInfo{MajorMinor: "1.22", PrereleaseKind: PrereleaseBeta, PrereleaseNum: "2"}, true},
+		{"main", VersionInfo{}, false},
+		{"", VersionInfo{}, false},
+		{"go1.21.3-1", VersionInfo{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := parseVersionTag(tt.tag)
+			if ok != tt.wantOK {
+				t.Fatalf("parseVersionTag(%q) ok = %v, want %v", tt.tag, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.MajorMinor != tt.want.MajorMinor || got.Patch != tt.want.Patch ||
+				got.PrereleaseKind != tt.want.PrereleaseKind || got.PrereleaseNum != tt.want.PrereleaseNum {
+				t.Errorf("parseVersionTag(%q) = %+v, want %+v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectTrack(t *testing.T) {
+	tests := []struct {
+		name   string
+		branch string
+		v      VersionInfo
+		want   Track
+	}{
+		{"boringcrypto branch", "dev.boringcrypto", VersionInfo{}, TrackBoringCrypto},
+		{"boringcrypto subbranch", "dev.boringcrypto/go1.21", VersionInfo{}, TrackBoringCrypto},
+		{"fips branch", "dev/official/go1.21-fips-preview", VersionInfo{}, TrackFIPS},
+		{"official non-fips branch", "dev/official/go1.21", VersionInfo{}, TrackStable},
+		{"rc prerelease", "release-branch.go1.21", VersionInfo{PrereleaseKind: PrereleaseRC}, TrackRC},
+		{"beta prerelease", "main", VersionInfo{PrereleaseKind: PrereleaseBeta}, TrackBeta},
+		{"stable", "release-branch.go1.21", VersionInfo{}, TrackStable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectTrack(tt.branch, tt.v); got != tt.want {
+				t.Errorf("detectTrack(%q, %+v) = %v, want %v", tt.branch, tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionInfoPrerelease(t *testing.T) {
+	tests := []struct {
+		name string
+		v    VersionInfo
+		want string
+	}{
+		{"final release", VersionInfo{PrereleaseKind: PrereleaseNone}, ""},
+		{"rc", VersionInfo{PrereleaseKind: PrereleaseRC, PrereleaseNum: "1"}, "rc1"},
+		{"beta", VersionInfo{PrereleaseKind: PrereleaseBeta, PrereleaseNum: "2"}, "beta2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.Prerelease(); got != tt.want {
+				t.Errorf("Prerelease() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
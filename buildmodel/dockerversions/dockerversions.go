@@ -0,0 +1,44 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package dockerversions represents the "arches" portion of the versions.json schema used by the
+// official Go Docker images repo. buildassets reuses this shape so a BuildAssets file can be
+// consumed directly by the Go Docker auto-update tooling without any translation step.
+package dockerversions
+
+// Arch describes a single architecture's build artifact, in the same shape the Go Docker images
+// repo's versions.json uses for its "arches" entries.
+type Arch struct {
+	// Env is the GOOS/GOARCH (and variant, e.g. GOARM) this artifact was built for.
+	Env ArchEnv `json:"env"`
+	// URL is where the artifact archive can be downloaded from.
+	URL string `json:"url"`
+	// SHA256 is the checksum of the artifact archive.
+	SHA256 string `json:"sha256"`
+	// Signature is a detached signature, base64-encoded, discovered from a sibling .sig or
+	// .sha256.sig file alongside the archive. Empty if the archive isn't signed. What exactly it's
+	// a signature over is recorded in SignatureKind, since the two file conventions sign different
+	// payloads.
+	Signature string `json:"signature,omitempty"`
+	// SignatureKind records what Signature is a detached signature over: SignatureKindArchive for
+	// a ".sig" file (the signature is over the archive's raw bytes), or SignatureKindSHA256 for a
+	// ".sha256.sig" file (the signature is over the recorded SHA256 checksum string). Empty if
+	// Signature is empty.
+	SignatureKind string `json:"signatureKind,omitempty"`
+}
+
+const (
+	// SignatureKindArchive means Signature is a detached signature over the archive's raw bytes,
+	// discovered from a sibling ".sig" file.
+	SignatureKindArchive = "archive"
+	// SignatureKindSHA256 means Signature is a detached signature over the recorded SHA256
+	// checksum string, discovered from a sibling ".sha256.sig" file. This is the smaller of the
+	// two payloads to sign, and is enough to transitively authenticate the archive.
+	SignatureKindSHA256 = "sha256"
+)
+
+// ArchEnv identifies the target environment variables Go's build uses to select an arch.
+type ArchEnv struct {
+	GOOS   string `json:"GOOS"`
+	GOARCH string `json:"GOARCH"`
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Command reprotest independently rebuilds a Microsoft Go release described by a BuildAssets JSON
+// file and checks that the result matches the published archives. It's a thin CLI wrapper around
+// buildassets.VerifyReproducibility, writing the resulting ReproReport as JSON and exiting non-zero
+// on any mismatch so it can gate a pipeline.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/microsoft/go-infra/buildmodel/buildassets"
+)
+
+func main() {
+	assetsFile := flag.String("assets", "", "Path to the BuildAssets JSON file to verify.")
+	sourceRepo := flag.String("source-repo", "", "Git repo URL (or local path) to clone and rebuild from.")
+	sourceCommit := flag.String("source-commit", "", "Commit to check out before rebuilding. Defaults to the commit recorded in -assets, if any.")
+	buildScript := flag.String("build-script", "eng/_util/cibuild-gorelease.sh", "Path, relative to the source tree root, of the script that produces release archives.")
+	workDir := flag.String("work", "", "Scratch directory for rebuilds. Defaults to a temp dir.")
+	originalArtifacts := flag.String("artifacts", "", "Directory containing the originally published archives, used to diff mismatches.")
+	full := flag.Bool("full", false, "Rebuild using the bootstrap toolchain given by -bootstrap-toolchain, rather than the host go. The toolchain is not looked up from the source tree.")
+	bootstrap := flag.String("bootstrap-toolchain", "", "Path to the bootstrap go binary to use when -full is set.")
+	parallel := flag.Int("parallel", 1, "Maximum number of arches to rebuild concurrently.")
+	out := flag.String("o", "", "Path to write the ReproReport JSON to. Defaults to stdout.")
+	flag.Parse()
+
+	if *assetsFile == "" || *sourceRepo == "" {
+		flag.Usage()
+		log.Fatal("reprotest: -assets and -source-repo are required")
+	}
+
+	assets, err := readBuildAssets(*assetsFile)
+	if err != nil {
+		log.Fatalf("reprotest: %v", err)
+	}
+
+	commit := *sourceCommit
+	if commit == "" {
+		commit = assets.CommitHash
+	}
+	if commit == "" {
+		log.Fatal("reprotest: -assets has no recorded CommitHash; pass -source-commit explicitly")
+	}
+
+	report, err := buildassets.VerifyReproducibility(context.Background(), assets, commit, buildassets.VerifyReproducibilityOptions{
+		Rebuilder: buildassets.ScriptRebuilder{
+			SourceRepoURL:           *sourceRepo,
+			BuildScriptRelativePath: *buildScript,
+		},
+		WorkDir:              *workDir,
+		OriginalArtifactsDir: *originalArtifacts,
+		Full:                 *full,
+		BootstrapToolchain:   *bootstrap,
+		MaxParallel:          *parallel,
+	})
+	if err != nil {
+		log.Fatalf("reprotest: %v", err)
+	}
+
+	if err := writeReport(report, *out); err != nil {
+		log.Fatalf("reprotest: %v", err)
+	}
+
+	if !report.AllMatched() {
+		os.Exit(1)
+	}
+}
+
+func readBuildAssets(path string) (*buildassets.BuildAssets, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open assets file: %w", err)
+	}
+	defer f.Close()
+
+	var assets buildassets.BuildAssets
+	if err := json.NewDecoder(f).Decode(&assets); err != nil {
+		return nil, fmt.Errorf("unable to parse assets file: %w", err)
+	}
+	return &assets, nil
+}
+
+func writeReport(report *buildassets.ReproReport, out string) error {
+	if out == "" {
+		return report.WriteJSON(os.Stdout)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("unable to create report file: %w", err)
+	}
+	defer f.Close()
+	return report.WriteJSON(f)
+}
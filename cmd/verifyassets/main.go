@@ -0,0 +1,132 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Command verifyassets offline-validates a published BuildAssets manifest against a directory of
+// downloaded artifacts, checking both the manifest's own signature and each arch's archive
+// signature, and that the downloaded archives actually match the checksums the manifest records.
+// It is meant to be run by a maintainer (or a gated auto-update pipeline) before trusting a
+// manifest that will drive a Go Docker update.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+
+	"github.com/microsoft/go-infra/buildmodel/buildassets"
+)
+
+func main() {
+	assetsFile := flag.String("assets", "", "Path to the BuildAssets JSON file to verify.")
+	artifactsDir := flag.String("artifacts", "", "Directory of downloaded artifacts to validate against the manifest. If set, each arch's local archive is hashed and compared to its recorded SHA256 before any signature is trusted.")
+	keyring := flag.String("gpg-keyring", "", "Path to a GPG public keyring to verify signatures against. If set, GPG signatures are checked.")
+	cosignIdentity := flag.String("cosign-identity-regexp", "", "Certificate identity regexp to verify cosign signatures against. If set (with -cosign-issuer), cosign signatures are checked.")
+	cosignIssuer := flag.String("cosign-issuer", "", "OIDC issuer to verify cosign signatures against.")
+	skipArches := flag.Bool("skip-arch-signatures", false, "Skip verifying each arch's individual archive signature.")
+	flag.Parse()
+
+	if *assetsFile == "" {
+		flag.Usage()
+		log.Fatal("verifyassets: -assets is required")
+	}
+	if *keyring == "" && (*cosignIdentity == "" || *cosignIssuer == "") {
+		log.Fatal("verifyassets: at least one of -gpg-keyring or -cosign-identity-regexp/-cosign-issuer must be set")
+	}
+
+	assets, err := readBuildAssets(*assetsFile)
+	if err != nil {
+		log.Fatalf("verifyassets: %v", err)
+	}
+
+	if *artifactsDir != "" {
+		if err := verifyArtifactChecksums(assets, *artifactsDir); err != nil {
+			log.Fatalf("verifyassets: downloaded artifacts don't match the manifest, refusing to trust any signature: %v", err)
+		}
+		fmt.Println("verifyassets: all downloaded artifacts match their recorded SHA256")
+	}
+
+	var source buildassets.ArtifactSource
+	if *artifactsDir != "" {
+		source = buildassets.NewLocalDirArtifactSource(*artifactsDir)
+	}
+
+	var verifiers []buildassets.Verifier
+	if *keyring != "" {
+		verifiers = append(verifiers, buildassets.GPGVerifier{TrustedKeyringPath: *keyring})
+	}
+	if *cosignIdentity != "" && *cosignIssuer != "" {
+		verifiers = append(verifiers, buildassets.CosignVerifier{
+			CertificateIdentityRegexp: *cosignIdentity,
+			CertificateOIDCIssuer:     *cosignIssuer,
+		})
+	}
+
+	ok := true
+	for _, v := range verifiers {
+		if err := assets.VerifySummary(v); err != nil {
+			fmt.Fprintf(os.Stderr, "verifyassets: manifest signature check failed: %v\n", err)
+			ok = false
+			continue
+		}
+		fmt.Printf("verifyassets: manifest %q signature OK\n", v.Name())
+
+		if !*skipArches {
+			if err := assets.VerifyArchSignatures(v, source); err != nil {
+				fmt.Fprintf(os.Stderr, "verifyassets: arch signature check failed: %v\n", err)
+				ok = false
+				continue
+			}
+			fmt.Printf("verifyassets: all arch %q signatures OK\n", v.Name())
+		}
+	}
+
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// verifyArtifactChecksums hashes each arch's archive in artifactsDir and compares it to the
+// SHA256 recorded in assets, so a signature that's only internally consistent with the manifest
+// (but whose corresponding downloaded archive was corrupted or substituted) doesn't pass silently.
+func verifyArtifactChecksums(assets *buildassets.BuildAssets, artifactsDir string) error {
+	for _, a := range assets.Arches {
+		name := path.Base(a.URL)
+		filePath := path.Join(artifactsDir, name)
+
+		f, err := os.Open(filePath)
+		if err != nil {
+			return fmt.Errorf("arch %v: unable to open %v: %w", a.URL, filePath, err)
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("arch %v: unable to hash %v: %w", a.URL, filePath, err)
+		}
+
+		sum := fmt.Sprintf("%x", h.Sum(nil))
+		if sum != a.SHA256 {
+			return fmt.Errorf("arch %v: %v has sha256 %v, manifest records %v", a.URL, filePath, sum, a.SHA256)
+		}
+	}
+	return nil
+}
+
+func readBuildAssets(path string) (*buildassets.BuildAssets, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open assets file: %w", err)
+	}
+	defer f.Close()
+
+	var assets buildassets.BuildAssets
+	if err := json.NewDecoder(f).Decode(&assets); err != nil {
+		return nil, fmt.Errorf("unable to parse assets file: %w", err)
+	}
+	return &assets, nil
+}